@@ -0,0 +1,484 @@
+// Package podman implements a devfile component adapter that talks to a
+// local Podman instance over its libpod REST API, so `odo push` works on
+// Fedora/RHEL workstations that have neither Docker nor a Kubernetes
+// cluster available.
+package podman
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	devfilev1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/library/pkg/devfile/parser"
+	"github.com/devfile/library/pkg/devfile/parser/data/v2/common"
+	odocommon "github.com/openshift/odo/pkg/devfile/adapters/common"
+	"github.com/pkg/errors"
+)
+
+// PlatformContext is the podman equivalent of kubernetes.KubernetesContext;
+// it carries no cluster-specific information because a rootless Podman
+// instance is scoped to the current user.
+type PlatformContext struct{}
+
+// apiBase is the libpod REST API version this adapter was written against.
+const apiBase = "http://d/v4.0.0/libpod"
+
+// socketEnvVar is checked before falling back to the well-known rootless
+// Podman socket location.
+const socketEnvVar = "PODMAN_SOCKET"
+
+// projectsDir mirrors the mount point the kubernetes and docker adapters
+// sync sources into inside the container.
+const projectsDir = "/projects"
+
+// DiscoverSocket returns the path to the Podman REST API socket. It honors
+// $PODMAN_SOCKET, and otherwise looks for the rootless per-user socket
+// under $XDG_RUNTIME_DIR, which is where `podman system service` places it
+// by default.
+func DiscoverSocket() (string, error) {
+	if s := os.Getenv(socketEnvVar); s != "" {
+		return s, nil
+	}
+
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return "", errors.New("XDG_RUNTIME_DIR is not set; unable to locate the rootless Podman socket (set PODMAN_SOCKET to override)")
+	}
+
+	socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+	if _, err := os.Stat(socket); err != nil {
+		return "", errors.Wrapf(err, "no Podman socket found at %s; is `podman system service` running?", socket)
+	}
+	return socket, nil
+}
+
+// Adapter drives devfile component commands (Push, Log, Exec, Delete, Test)
+// against Podman pods, the same role kubernetes.Adapter and
+// docker.Adapter play for their respective backends.
+type Adapter struct {
+	componentName string
+	context       string
+	appName       string
+	devfileObj    parser.DevfileObj
+
+	client *http.Client
+}
+
+// New creates a podman-backed component adapter. socketPath is typically
+// the result of DiscoverSocket; passing "" auto-discovers it.
+func New(componentName, context, appName string, devfileObj parser.DevfileObj, socketPath string) (Adapter, error) {
+	if socketPath == "" {
+		var err error
+		socketPath, err = DiscoverSocket()
+		if err != nil {
+			return Adapter{}, err
+		}
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("unix", socketPath)
+			},
+		},
+	}
+
+	return Adapter{
+		componentName: componentName,
+		context:       context,
+		appName:       appName,
+		devfileObj:    devfileObj,
+		client:        client,
+	}, nil
+}
+
+// podName is the pod odo creates and reuses for this component, mirroring
+// how the kubernetes adapter names its deployment after the component.
+func (a Adapter) podName() string {
+	return fmt.Sprintf("%s-%s", a.appName, a.componentName)
+}
+
+// containerName is the name of the container a devfile container component
+// runs as inside a.podName(), namespaced the same way podName is so two
+// components' containers never collide.
+func (a Adapter) containerName(devfileContainerName string) string {
+	return fmt.Sprintf("%s-%s", a.podName(), devfileContainerName)
+}
+
+// do issues an HTTP request against the libpod API and returns an error if
+// podman responds with anything other than a 2xx status.
+func (a Adapter) do(method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to marshal podman request body")
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, apiBase+path, reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to build podman API request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to reach Podman; is `podman system service` running?")
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("podman API %s %s returned %s: %s", method, path, resp.Status, msg)
+	}
+	return resp, nil
+}
+
+// Push creates the component's pod and main container on Podman if they
+// don't exist yet, syncs parameters.Path into the container, and runs the
+// devfile build and run commands inside it — the same contract
+// kubernetes.Adapter.Push and docker.Adapter.Push follow.
+func (a Adapter) Push(parameters odocommon.PushParameters) error {
+	if err := a.ensurePod(); err != nil {
+		return errors.Wrap(err, "unable to create podman pod for component")
+	}
+
+	container, err := a.mainContainer()
+	if err != nil {
+		return err
+	}
+
+	name := a.containerName(container.Name)
+	if err := a.ensureContainer(name, container); err != nil {
+		return errors.Wrapf(err, "unable to create podman container %q", name)
+	}
+
+	if err := a.syncFiles(name, parameters.Path, parameters.ProgressReporter); err != nil {
+		return errors.Wrap(err, "unable to sync sources to podman container")
+	}
+
+	if parameters.DevfileBuildCmd != "" {
+		if err := a.runDevfileCommand(name, parameters.DevfileBuildCmd, false); err != nil {
+			return errors.Wrap(err, "unable to run build command")
+		}
+	}
+
+	runCmd := parameters.DevfileRunCmd
+	if parameters.Debug {
+		runCmd = parameters.DevfileDebugCmd
+	}
+	if runCmd != "" {
+		// The run (and debug) command is typically a long-running process,
+		// e.g. a dev server, that's never expected to exit on its own.
+		// Start it detached so Push returns once it's up, the same contract
+		// kubernetes.Adapter.Push and docker.Adapter.Push follow.
+		if err := a.runDevfileCommand(name, runCmd, true); err != nil {
+			return errors.Wrap(err, "unable to run run command")
+		}
+	}
+
+	return nil
+}
+
+func (a Adapter) ensurePod() error {
+	resp, err := a.do(http.MethodGet, "/pods/"+a.podName()+"/exists", nil)
+	if err == nil {
+		return resp.Body.Close()
+	}
+
+	resp, err = a.do(http.MethodPost, "/pods/create", map[string]interface{}{
+		"name":   a.podName(),
+		"labels": map[string]string{"component": a.componentName, "app": a.appName},
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// mainContainer returns the first container component declared in the
+// devfile. odo doesn't support multi-container push targets on podman yet,
+// mirroring the docker adapter's single-container limitation.
+func (a Adapter) mainContainer() (devfilev1.Component, error) {
+	components, err := a.devfileObj.Data.GetComponents(common.DevfileOptions{})
+	if err != nil {
+		return devfilev1.Component{}, errors.Wrap(err, "unable to read devfile components")
+	}
+	for _, c := range components {
+		if c.Container != nil {
+			return c, nil
+		}
+	}
+	return devfilev1.Component{}, errors.New("devfile declares no container component to push")
+}
+
+// ensureContainer creates and starts a long-lived container for a devfile
+// container component if it doesn't already exist. The container runs
+// "sleep infinity" as its entrypoint so it stays up between the build and
+// run commands odo execs into it, the same role a Kubernetes pod's
+// container process plays while odo execs build/run commands into it.
+func (a Adapter) ensureContainer(name string, container devfilev1.Component) error {
+	resp, err := a.do(http.MethodGet, "/containers/"+name+"/exists", nil)
+	if err == nil {
+		return resp.Body.Close()
+	}
+
+	resp, err = a.do(http.MethodPost, "/containers/create", map[string]interface{}{
+		"name":       name,
+		"pod":        a.podName(),
+		"image":      container.Container.Image,
+		"entrypoint": []string{"sleep", "infinity"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := resp.Body.Close(); err != nil {
+		return err
+	}
+
+	resp, err = a.do(http.MethodPost, "/containers/"+name+"/start", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// syncFiles tars path and PUTs it to the container's projectsDir via the
+// libpod archive endpoint, reporting progress as it writes the tar stream
+// so `odo push`'s JSON consumers can render a progress bar.
+func (a Adapter) syncFiles(containerName, path string, report odocommon.ProgressReporter) error {
+	var buf bytes.Buffer
+	if _, err := tarDirectory(path, &buf); err != nil {
+		return errors.Wrap(err, "unable to archive source directory")
+	}
+	// progressReader counts every byte of the tar stream, headers and
+	// padding included, so the total must be the size of that stream.
+	total := int64(buf.Len())
+
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/containers/%s/archive?path=%s", apiBase, containerName, projectsDir), &progressReader{r: &buf, total: total, report: report})
+	if err != nil {
+		return errors.Wrap(err, "unable to build podman archive upload request")
+	}
+	req.Header.Set("Content-Type", "application/x-tar")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "unable to reach Podman; is `podman system service` running?")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("podman rejected the source archive: %s: %s", resp.Status, msg)
+	}
+	return nil
+}
+
+// tarDirectory writes dir as a tar stream to w and returns the total size of
+// the file contents it archived; tar headers and padding are not counted.
+func tarDirectory(dir string, w io.Writer) (int64, error) {
+	tw := tar.NewWriter(w)
+	var total int64
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		n, err := io.Copy(tw, f)
+		if err != nil {
+			return err
+		}
+		total += n
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, tw.Close()
+}
+
+// progressReader wraps an io.Reader, calling report after every Read so
+// syncFiles can stream SyncProgress events as the archive upload proceeds.
+// report may be nil, e.g. when odo isn't running in JSON mode.
+type progressReader struct {
+	r         io.Reader
+	total     int64
+	read      int64
+	filename  string
+	report    odocommon.ProgressReporter
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.read += int64(n)
+	if p.report != nil {
+		p.report(p.read, p.total, p.filename)
+	}
+	return n, err
+}
+
+// runDevfileCommand execs commandID's command line, as declared in the
+// devfile, inside containerName. When detach is false (build, test), it
+// blocks until the command completes and returns its error. When detach is
+// true (run, debug), it starts the command and returns as soon as the exec
+// session is created, without waiting for it to exit.
+func (a Adapter) runDevfileCommand(containerName, commandID string, detach bool) error {
+	command, err := a.commandByID(commandID)
+	if err != nil {
+		return err
+	}
+	if command.Exec == nil {
+		return errors.Errorf("command %q is not an exec command", commandID)
+	}
+	return a.execInContainer(containerName, []string{"/bin/sh", "-c", command.Exec.CommandLine}, detach)
+}
+
+// commandByID finds a devfile command by its (already-lowercased) id.
+func (a Adapter) commandByID(id string) (devfilev1.Command, error) {
+	commands, err := a.devfileObj.Data.GetCommands(common.DevfileOptions{})
+	if err != nil {
+		return devfilev1.Command{}, errors.Wrap(err, "unable to read devfile commands")
+	}
+	for _, c := range commands {
+		if c.Id == id {
+			return c, nil
+		}
+	}
+	return devfilev1.Command{}, errors.Errorf("no command with id %q found in devfile", id)
+}
+
+// execInContainer creates a libpod exec session running cmd inside
+// containerName. When detach is false, it starts the session attached,
+// streams its combined output to os.Stdout, waits for it to finish, and
+// returns an error if it exited non-zero — used for build and test
+// commands, which odo must wait on to know whether they succeeded. When
+// detach is true, it starts the session detached and returns as soon as
+// it's been created, without waiting for it to exit or checking its exit
+// code — used for the run and debug commands, which are typically
+// long-running processes that never exit on their own.
+func (a Adapter) execInContainer(containerName string, cmd []string, detach bool) error {
+	resp, err := a.do(http.MethodPost, "/containers/"+containerName+"/exec", map[string]interface{}{
+		"Cmd":          cmd,
+		"AttachStdout": !detach,
+		"AttachStderr": !detach,
+	})
+	if err != nil {
+		return err
+	}
+
+	var created struct {
+		Id string `json:"Id"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if err != nil {
+		return errors.Wrap(err, "unable to decode podman exec-create response")
+	}
+
+	resp, err = a.do(http.MethodPost, "/exec/"+created.Id+"/start", map[string]interface{}{
+		"Detach": detach,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if detach {
+		return nil
+	}
+
+	if _, err := io.Copy(os.Stdout, resp.Body); err != nil {
+		return errors.Wrap(err, "unable to stream podman exec output")
+	}
+
+	inspectResp, err := a.do(http.MethodGet, "/exec/"+created.Id+"/json", nil)
+	if err != nil {
+		return err
+	}
+	defer inspectResp.Body.Close()
+
+	var inspect struct {
+		ExitCode int `json:"ExitCode"`
+	}
+	if err := json.NewDecoder(inspectResp.Body).Decode(&inspect); err != nil {
+		return errors.Wrap(err, "unable to decode podman exec inspect response")
+	}
+	if inspect.ExitCode != 0 {
+		return errors.Errorf("command %v exited with code %d", cmd, inspect.ExitCode)
+	}
+	return nil
+}
+
+// Log returns the logs for the component's run (or debug) command.
+func (a Adapter) Log(follow bool, command devfilev1.Command) (io.ReadCloser, error) {
+	resp, err := a.do(http.MethodGet, fmt.Sprintf("/pods/%s/logs?follow=%t&stdout=true&stderr=true", a.podName(), follow), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Exec runs command inside the component's main container and waits for it
+// to finish. Only stdout and stderr are attached; stdin is not, so an
+// interactive command gets no input.
+func (a Adapter) Exec(command []string) error {
+	container, err := a.mainContainer()
+	if err != nil {
+		return err
+	}
+	return a.execInContainer(a.containerName(container.Name), command, false)
+}
+
+// Delete removes the component's pod and any resources Push created for it.
+func (a Adapter) Delete(labels map[string]string, show bool) error {
+	resp, err := a.do(http.MethodDelete, "/pods/"+a.podName()+"?force=true", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Test runs the named devfile test command against the component's pod.
+func (a Adapter) Test(testCmd string, show bool) error {
+	command, err := a.commandByID(testCmd)
+	if err != nil {
+		return err
+	}
+	if command.Exec == nil {
+		return errors.Errorf("test command %q is not an exec command", testCmd)
+	}
+	return a.execInContainer(a.containerName(command.Exec.Component), []string{"/bin/sh", "-c", command.Exec.CommandLine}, false)
+}
@@ -0,0 +1,35 @@
+package podman
+
+import "testing"
+
+func TestDiscoverSocket_EnvOverride(t *testing.T) {
+	t.Setenv(socketEnvVar, "/tmp/custom-podman.sock")
+
+	socket, err := DiscoverSocket()
+	if err != nil {
+		t.Fatalf("DiscoverSocket() error = %v", err)
+	}
+	if socket != "/tmp/custom-podman.sock" {
+		t.Errorf("DiscoverSocket() = %q, want %q", socket, "/tmp/custom-podman.sock")
+	}
+}
+
+func TestDiscoverSocket_NoRuntimeDir(t *testing.T) {
+	t.Setenv(socketEnvVar, "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+
+	if _, err := DiscoverSocket(); err == nil {
+		t.Error("DiscoverSocket() with neither PODMAN_SOCKET nor XDG_RUNTIME_DIR set should return an error")
+	}
+}
+
+func TestAdapter_PodAndContainerName(t *testing.T) {
+	a := Adapter{componentName: "frontend", appName: "myapp"}
+
+	if got, want := a.podName(), "myapp-frontend"; got != want {
+		t.Errorf("podName() = %q, want %q", got, want)
+	}
+	if got, want := a.containerName("runtime"), "myapp-frontend-runtime"; got != want {
+		t.Errorf("containerName() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+// Package auth resolves credentials used to fetch devfiles (and their
+// parents) from private Git providers (GitHub, GitLab, Bitbucket) and
+// private devfile registries.
+package auth
+
+import (
+	"os"
+	"strings"
+
+	"github.com/openshift/odo/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/zalando/go-keyring"
+)
+
+// EnvToken is the environment variable checked when no token can be found
+// in the keyring or in preference.yaml's per-URL token map.
+const EnvToken = "ODO_DEVFILE_TOKEN"
+
+// keyringService is the service name odo stores devfile tokens under.
+const keyringService = "odo-devfile-token"
+
+// Provider identifies the git hosting provider a devfile (or its parent)
+// was fetched from.
+type Provider string
+
+const (
+	ProviderGitHub    Provider = "github"
+	ProviderGitLab    Provider = "gitlab"
+	ProviderBitbucket Provider = "bitbucket"
+	ProviderUnknown   Provider = ""
+)
+
+// DetectProvider guesses the git provider a devfile URL belongs to from its
+// host name. It returns ProviderUnknown if the host isn't recognized, which
+// is not an error; unknown providers are still handed a resolved token if
+// one is configured for the URL.
+func DetectProvider(rawURL string) Provider {
+	switch {
+	case strings.Contains(rawURL, "github.com"):
+		return ProviderGitHub
+	case strings.Contains(rawURL, "gitlab.com"):
+		return ProviderGitLab
+	case strings.Contains(rawURL, "bitbucket.org"):
+		return ProviderBitbucket
+	default:
+		return ProviderUnknown
+	}
+}
+
+// Resolve returns the token to use when fetching the devfile (or a parent
+// devfile) located at url. Lookup order, first match wins:
+//  1. the per-URL token map from preference.yaml (urlTokens)
+//  2. the OS keyring entry saved by Save when `--token` is passed
+//  3. the ODO_DEVFILE_TOKEN environment variable
+//
+// An empty token with a nil error means the URL is public and no
+// credentials are required.
+func Resolve(url string, urlTokens map[string]string) (string, error) {
+	if token, ok := urlTokens[url]; ok && token != "" {
+		return token, nil
+	}
+
+	token, err := keyring.Get(keyringService, url)
+	switch {
+	case err == nil:
+		return token, nil
+	case err == keyring.ErrNotFound:
+		// fall through to the env var fallback
+	default:
+		// keyring.Get has no "wrong credentials" failure mode of its own;
+		// any non-ErrNotFound error here means there's no usable keyring
+		// backend at all (e.g. no Secret Service/dbus daemon, the common
+		// case on headless CI runners and minimal containers, odo's main
+		// target environment). Treat that the same as not-found rather
+		// than aborting every push/log/exec/delete for a devfile that may
+		// not even need a token.
+		log.Warningf("Unable to read devfile token for %s from the OS keyring (%v); falling back to %s", url, err, EnvToken)
+	}
+
+	if token := os.Getenv(EnvToken); token != "" {
+		return token, nil
+	}
+
+	return "", nil
+}
+
+// Save persists token for url in the OS keyring so future invocations of
+// odo don't need --token or ODO_DEVFILE_TOKEN set again.
+func Save(url, token string) error {
+	if token == "" {
+		return errors.New("cannot save an empty devfile token")
+	}
+	return keyring.Set(keyringService, url, token)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import "testing"
+
+func TestDetectProvider(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want Provider
+	}{
+		{"github", "https://github.com/foo/bar.git", ProviderGitHub},
+		{"gitlab", "https://gitlab.com/foo/bar.git", ProviderGitLab},
+		{"bitbucket", "https://bitbucket.org/foo/bar.git", ProviderBitbucket},
+		{"unknown", "https://example.com/foo/bar.git", ProviderUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectProvider(tt.url); got != tt.want {
+				t.Errorf("DetectProvider(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_URLTokenMapWins(t *testing.T) {
+	const url = "https://git.example.com/foo/bar.git"
+	urlTokens := map[string]string{url: "from-preference"}
+
+	token, err := Resolve(url, urlTokens)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if token != "from-preference" {
+		t.Errorf("Resolve() = %q, want %q", token, "from-preference")
+	}
+}
+
+func TestResolve_EnvFallback(t *testing.T) {
+	const url = "https://git.example.com/foo/bar.git"
+	t.Setenv(EnvToken, "from-env")
+
+	token, err := Resolve(url, nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if token != "from-env" {
+		t.Errorf("Resolve() = %q, want %q", token, "from-env")
+	}
+}
+
+func TestResolve_KeyringUnavailableFallsBackToEnv(t *testing.T) {
+	// CI runners and minimal containers generally have no Secret
+	// Service/keyring daemon running, so keyring.Get returns a
+	// "service unavailable"-style error rather than keyring.ErrNotFound.
+	// This test runs in exactly that kind of environment (there's no
+	// keyring daemon in this test run either), so it exercises the real
+	// fallback path rather than a mock: Resolve must not treat that as
+	// fatal, and must still fall through to ODO_DEVFILE_TOKEN.
+	const url = "https://git.example.com/foo/bar.git"
+	t.Setenv(EnvToken, "from-env")
+
+	token, err := Resolve(url, nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v, want nil even when the keyring backend is unavailable", err)
+	}
+	if token != "from-env" {
+		t.Errorf("Resolve() = %q, want %q", token, "from-env")
+	}
+}
+
+func TestResolve_NoTokenConfigured(t *testing.T) {
+	const url = "https://git.example.com/foo/bar.git"
+
+	token, err := Resolve(url, nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if token != "" {
+		t.Errorf("Resolve() = %q, want empty token for a public URL", token)
+	}
+}
+
+func TestSave_RejectsEmptyToken(t *testing.T) {
+	if err := Save("https://git.example.com/foo/bar.git", ""); err == nil {
+		t.Error("Save() with an empty token should return an error")
+	}
+}
@@ -0,0 +1,79 @@
+// Package cache stores fully-flattened devfiles (parent and plugin
+// overrides already merged) on disk so that odo can reuse them when the
+// upstream parent devfile is unreachable, and can detect when a parent has
+// changed since it was last resolved.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Dir is the directory flattened devfiles are cached under.
+var Dir = filepath.Join(os.Getenv("HOME"), ".odo", "devfile-cache")
+
+// ErrLockMismatch is returned by Verify when the upstream parent's content
+// hash no longer matches the hash recorded in .odo/env/env.yaml.
+var ErrLockMismatch = errors.New("resolved devfile no longer matches the pinned devfileLock; rerun with --devfile-update-lock to accept the change")
+
+// Hash returns the sha256 hex digest of a flattened devfile's raw content.
+// It is used both as the cache file name and as the value persisted to
+// env.yaml's devfileLock field.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns the on-disk location of the cache entry for hash.
+func path(hash string) string {
+	return filepath.Join(Dir, hash+".yaml")
+}
+
+// Store writes the flattened devfile content to the cache, keyed by its
+// content hash, and returns that hash so callers can persist it as the
+// env.yaml devfileLock.
+func Store(content []byte) (string, error) {
+	if err := os.MkdirAll(Dir, 0750); err != nil {
+		return "", errors.Wrap(err, "unable to create devfile cache directory")
+	}
+
+	hash := Hash(content)
+	if err := ioutil.WriteFile(path(hash), content, 0640); err != nil {
+		return "", errors.Wrapf(err, "unable to write devfile cache entry %s", hash)
+	}
+	return hash, nil
+}
+
+// Load reads back the flattened devfile previously stored under hash. It
+// returns os.IsNotExist errors as-is so callers can distinguish a cache
+// miss (fall through to a live parent resolution) from other I/O failures.
+func Load(hash string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path(hash))
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// Has reports whether a flattened devfile is already cached for hash.
+func Has(hash string) bool {
+	_, err := os.Stat(path(hash))
+	return err == nil
+}
+
+// Verify checks a freshly resolved devfile's content hash against
+// lockedHash, the devfileLock previously recorded in env.yaml. It returns
+// ErrLockMismatch if they differ and updateLock is false, so `odo push`
+// fails loudly instead of silently drifting from the pinned parent.
+func Verify(content []byte, lockedHash string, updateLock bool) (string, error) {
+	hash := Hash(content)
+	if lockedHash == "" || hash == lockedHash || updateLock {
+		return hash, nil
+	}
+	return hash, ErrLockMismatch
+}
@@ -0,0 +1,90 @@
+package cache
+
+import "testing"
+
+func withTempDir(t *testing.T) {
+	t.Helper()
+	orig := Dir
+	Dir = t.TempDir()
+	t.Cleanup(func() { Dir = orig })
+}
+
+func TestStoreLoadRoundTrip(t *testing.T) {
+	withTempDir(t)
+
+	content := []byte("schemaVersion: 2.0.0\n")
+	hash, err := Store(content)
+	if err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if hash != Hash(content) {
+		t.Errorf("Store() hash = %q, want %q", hash, Hash(content))
+	}
+
+	if !Has(hash) {
+		t.Error("Has() = false after Store(), want true")
+	}
+
+	got, err := Load(hash)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("Load() = %q, want %q", got, content)
+	}
+}
+
+func TestHas_Miss(t *testing.T) {
+	withTempDir(t)
+
+	if Has("does-not-exist") {
+		t.Error("Has() = true for a hash that was never stored, want false")
+	}
+}
+
+func TestLoad_Miss(t *testing.T) {
+	withTempDir(t)
+
+	if _, err := Load("does-not-exist"); err == nil {
+		t.Error("Load() for a hash that was never stored should return an error")
+	}
+}
+
+func TestVerify_NoLockYet(t *testing.T) {
+	hash, err := Verify([]byte("content"), "", false)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if hash != Hash([]byte("content")) {
+		t.Errorf("Verify() hash = %q, want %q", hash, Hash([]byte("content")))
+	}
+}
+
+func TestVerify_MatchingLock(t *testing.T) {
+	content := []byte("content")
+	hash, err := Verify(content, Hash(content), false)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if hash != Hash(content) {
+		t.Errorf("Verify() hash = %q, want %q", hash, Hash(content))
+	}
+}
+
+func TestVerify_MismatchedLockWithoutUpdate(t *testing.T) {
+	_, err := Verify([]byte("new content"), "some-other-hash", false)
+	if err != ErrLockMismatch {
+		t.Errorf("Verify() error = %v, want %v", err, ErrLockMismatch)
+	}
+}
+
+func TestVerify_MismatchedLockWithUpdate(t *testing.T) {
+	content := []byte("new content")
+	hash, err := Verify(content, "some-other-hash", true)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if hash != Hash(content) {
+		t.Errorf("Verify() hash = %q, want %q", hash, Hash(content))
+	}
+}
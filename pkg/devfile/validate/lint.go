@@ -0,0 +1,185 @@
+package validate
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/devfile/library/pkg/devfile/parser"
+	"github.com/devfile/library/pkg/devfile/parser/data/v2/common"
+	"github.com/pkg/errors"
+)
+
+// Severity classifies how urgently a Diagnostic should be addressed.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Diagnostic is one finding produced by a lint Rule.
+type Diagnostic struct {
+	// Severity is how urgently this should be addressed.
+	Severity Severity
+	// Location is a JSON pointer (RFC 6901) into the devfile pointing at
+	// the offending node, e.g. "/commands/2/id". The deprecated-type-keys
+	// rule, which scans the raw source rather than the parsed devfile, sets
+	// it to "line N" instead; it is not a JSON pointer there.
+	Location string
+	// Rule is the ID of the Rule that produced this diagnostic.
+	Rule string
+	// Message is a human-readable description of the problem.
+	Message string
+	// Line is the 1-indexed line in the devfile's raw content this
+	// diagnostic is about, for printing source-line context alongside
+	// Location/Message. It is 0 when a Rule can't locate a source line for
+	// the finding. A finding that spans several commands points at the
+	// first of them.
+	Line int
+	// Fix rewrites devObj to resolve the diagnostic, in place. Fix is nil
+	// for diagnostics that have no safe automatic fix.
+	Fix Fixer
+}
+
+// Fixer rewrites devObj to resolve a Diagnostic. It is only ever called on
+// diagnostics that set one; `odo devfile lint --fix` calls each in turn and
+// re-serializes the devfile once every fix has run.
+type Fixer func(devObj *parser.DevfileObj) error
+
+// Rule is a single lint check, registered via Register so new checks land
+// as small, self-contained additions instead of growing Lint itself.
+type Rule struct {
+	// ID is a short, stable, lowercase-with-hyphens identifier, e.g.
+	// "lowercase-command-id".
+	ID string
+	// Check inspects devObj and returns zero or more diagnostics. content
+	// is devObj's raw, not-yet-parsed source, for rules that need to
+	// locate a source line (via locateLine) or that check something the
+	// parsed, schema-normalized devObj no longer carries, such as a
+	// deprecated key the parser silently drops.
+	Check func(devObj parser.DevfileObj, content []byte) []Diagnostic
+}
+
+// registry holds every Rule registered via Register. It is a package-level
+// plugin registry rather than a hardcoded list so that new rules can be
+// added from an init() in their own file, close to what they check.
+var registry []Rule
+
+// Register adds rule to the set Lint runs. It is meant to be called from an
+// init() function; it is not safe to call concurrently with Lint.
+func Register(rule Rule) {
+	registry = append(registry, rule)
+}
+
+// Lint runs every registered Rule against devObj and returns their combined
+// diagnostics. It is a superset of ValidateDevfileData: where
+// ValidateDevfileData returns on the first schema error it hits, Lint
+// collects every style/best-practice issue so `odo devfile lint` can report
+// them all in one pass. content is devObj's raw source, passed to every
+// Rule so it can locate a source line for its diagnostics.
+func Lint(devObj parser.DevfileObj, content []byte) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, rule := range registry {
+		diagnostics = append(diagnostics, rule.Check(devObj, content)...)
+	}
+	return diagnostics
+}
+
+func init() {
+	Register(Rule{
+		ID:    "lowercase-command-id",
+		Check: checkLowercaseCommandID,
+	})
+	Register(Rule{
+		ID:    "devfile-data-valid",
+		Check: checkDevfileData,
+	})
+}
+
+// checkDevfileData reports the first error ValidateDevfileData finds, if any.
+func checkDevfileData(devObj parser.DevfileObj, content []byte) []Diagnostic {
+	if err := ValidateDevfileData(devObj.Data); err != nil {
+		return []Diagnostic{{Severity: SeverityError, Location: "", Rule: "devfile-data-valid", Message: err.Error()}}
+	}
+	return nil
+}
+
+// checkLowercaseCommandID flags command ids that aren't already lowercase.
+// devfilePushInner and friends strings.ToLower() the --build-command/
+// --run-command/--debug-command flags before matching them against command
+// ids, so a mixed-case id can never be selected from the CLI.
+func checkLowercaseCommandID(devObj parser.DevfileObj, content []byte) []Diagnostic {
+	commands, err := devObj.Data.GetCommands(common.DevfileOptions{})
+	if err != nil {
+		return nil
+	}
+
+	var diagnostics []Diagnostic
+	for i, cmd := range commands {
+		lower := strings.ToLower(cmd.Id)
+		if cmd.Id == lower {
+			continue
+		}
+
+		id := cmd.Id
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Location: "/commands/" + strconv.Itoa(i) + "/id",
+			Rule:     "lowercase-command-id",
+			Message:  "command id \"" + id + "\" is not lowercase; odo lowercases build/run/debug command flags before matching, so this command can never be selected",
+			Line:     locateLine(content, "id:", id),
+			Fix: func(devObj *parser.DevfileObj) error {
+				return renameCommand(devObj, id, lower)
+			},
+		})
+	}
+	return diagnostics
+}
+
+// locateLine does a best-effort scan of content for the first line whose
+// trimmed text starts with key and contains value (e.g. key="id:",
+// value="Build" matches a line like `    id: Build`), returning its
+// 1-indexed line number, or 0 if no line matches. It's a plain text scan
+// rather than a proper YAML-position lookup because nothing upstream of
+// Lint keeps the parsed devfile's node positions around.
+func locateLine(content []byte, key, value string) int {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	line := 0
+	for scanner.Scan() {
+		line++
+		trimmed := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(trimmed, key) && strings.Contains(trimmed, value) {
+			return line
+		}
+	}
+	return 0
+}
+
+// renameCommand changes the id of the command named from to to, leaving
+// every other field untouched. It can't go through UpdateCommand, which
+// matches the command to replace by the Id of the struct it's given: since
+// that's exactly the field being renamed here, passing it the already-
+// renamed struct would have it look for to and never find from. Instead,
+// it deletes the old command and re-adds the renamed copy.
+func renameCommand(devObj *parser.DevfileObj, from, to string) error {
+	commands, err := devObj.Data.GetCommands(common.DevfileOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range commands {
+		if cmd.Id != from {
+			continue
+		}
+		renamed := cmd
+		renamed.Id = to
+		if err := devObj.Data.DeleteCommand(from); err != nil {
+			return err
+		}
+		return devObj.Data.AddCommands(renamed)
+	}
+	return errors.Errorf("command %q no longer exists in the devfile", from)
+}
@@ -0,0 +1,48 @@
+package validate
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/devfile/library/pkg/devfile/parser"
+)
+
+func init() {
+	Register(Rule{
+		ID:    "deprecated-type-keys",
+		Check: checkDeprecatedTypeKeys,
+	})
+}
+
+// checkDeprecatedTypeKeys flags a bare "type:" key anywhere in the devfile.
+// Devfile v1 selected a command's or component's kind with a "type: exec"-
+// style key; v2 replaced it with a typed field (exec:, apply:, composite:,
+// container:, kubernetes:, ...) and no longer recognizes "type" at all, so
+// the parsed devObj has nowhere to carry it - it's silently dropped instead
+// of raising a schema error. This rule works off content, the devfile's raw
+// source, since that's the only place a leftover v1 "type:" key still shows
+// up at all.
+func checkDeprecatedTypeKeys(devObj parser.DevfileObj, content []byte) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	line := 0
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line++
+		trimmed := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(trimmed, "type:") {
+			continue
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Location: "line " + strconv.Itoa(line),
+			Rule:     "deprecated-type-keys",
+			Message:  "\"" + trimmed + "\" uses the devfile v1 \"type:\" key, which v2 no longer recognizes; replace it with a typed field (exec:, apply:, composite:, container:, kubernetes:, ...)",
+			Line:     line,
+		})
+	}
+	return diagnostics
+}
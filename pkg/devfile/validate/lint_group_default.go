@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"strings"
+
+	devfilev1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/library/pkg/devfile/parser"
+	"github.com/devfile/library/pkg/devfile/parser/data/v2/common"
+)
+
+func init() {
+	Register(Rule{
+		ID:    "missing-commandline-default",
+		Check: checkMissingCommandLineDefault,
+	})
+}
+
+// checkMissingCommandLineDefault flags a command group (build, run, test,
+// debug) that has more than one command but none marked as its default.
+// --build-command/--run-command/etc. are optional precisely because odo
+// falls back to the group's default command when they're not passed; a
+// group with no default and more than one candidate leaves that fallback
+// with nothing to pick, so e.g. a plain `odo push` with no --build-command
+// fails instead of silently picking the "wrong" build command.
+func checkMissingCommandLineDefault(devObj parser.DevfileObj, content []byte) []Diagnostic {
+	commands, err := devObj.Data.GetCommands(common.DevfileOptions{})
+	if err != nil {
+		return nil
+	}
+
+	byKind := map[devfilev1.CommandGroupKind][]devfilev1.Command{}
+	for _, cmd := range commands {
+		group := common.GetGroup(cmd)
+		if group == nil {
+			continue
+		}
+		byKind[group.Kind] = append(byKind[group.Kind], cmd)
+	}
+
+	var diagnostics []Diagnostic
+	for kind, cmds := range byKind {
+		if len(cmds) < 2 || hasDefault(cmds) {
+			continue
+		}
+
+		ids := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			ids[i] = cmd.Id
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Location: "/commands",
+			Rule:     "missing-commandline-default",
+			Message:  "the " + string(kind) + " command group has " + strings.Join(ids, ", ") + " but none is marked as the default; odo has no way to pick one when --" + string(kind) + "-command isn't passed",
+			Line:     locateLine(content, "id:", ids[0]),
+		})
+	}
+	return diagnostics
+}
+
+// hasDefault reports whether any command in cmds has its group's
+// IsDefault set.
+func hasDefault(cmds []devfilev1.Command) bool {
+	for _, cmd := range cmds {
+		if group := common.GetGroup(cmd); group != nil && group.IsDefault != nil && *group.IsDefault {
+			return true
+		}
+	}
+	return false
+}
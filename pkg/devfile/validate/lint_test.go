@@ -0,0 +1,77 @@
+package validate
+
+import (
+	"testing"
+
+	devfilev1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/library/pkg/devfile/parser"
+	"github.com/devfile/library/pkg/devfile/parser/data"
+	"github.com/devfile/library/pkg/devfile/parser/data/v2/common"
+)
+
+func TestRenameCommand_RoundTrips(t *testing.T) {
+	devfileData, err := data.NewDevfileData("2.1.0")
+	if err != nil {
+		t.Fatalf("NewDevfileData() error = %v", err)
+	}
+	if err := devfileData.AddCommands(devfilev1.Command{
+		Id: "Build",
+		CommandUnion: devfilev1.CommandUnion{
+			Exec: &devfilev1.ExecCommand{CommandLine: "make build"},
+		},
+	}); err != nil {
+		t.Fatalf("AddCommands() error = %v", err)
+	}
+	devObj := parser.DevfileObj{Data: devfileData}
+
+	if err := renameCommand(&devObj, "Build", "build"); err != nil {
+		t.Fatalf("renameCommand() error = %v", err)
+	}
+
+	commands, err := devObj.Data.GetCommands(common.DevfileOptions{})
+	if err != nil {
+		t.Fatalf("GetCommands() error = %v", err)
+	}
+	if len(commands) != 1 {
+		t.Fatalf("got %d commands after rename, want 1", len(commands))
+	}
+	if commands[0].Id != "build" {
+		t.Errorf("renamed command id = %q, want %q", commands[0].Id, "build")
+	}
+}
+
+func TestCheckLowercaseCommandID_FixRenames(t *testing.T) {
+	devfileData, err := data.NewDevfileData("2.1.0")
+	if err != nil {
+		t.Fatalf("NewDevfileData() error = %v", err)
+	}
+	if err := devfileData.AddCommands(devfilev1.Command{
+		Id: "Build",
+		CommandUnion: devfilev1.CommandUnion{
+			Exec: &devfilev1.ExecCommand{CommandLine: "make build"},
+		},
+	}); err != nil {
+		t.Fatalf("AddCommands() error = %v", err)
+	}
+	devObj := parser.DevfileObj{Data: devfileData}
+
+	diagnostics := checkLowercaseCommandID(devObj, nil)
+	if len(diagnostics) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(diagnostics))
+	}
+	if diagnostics[0].Fix == nil {
+		t.Fatal("diagnostic for a mixed-case command id should carry a Fix")
+	}
+
+	if err := diagnostics[0].Fix(&devObj); err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	commands, err := devObj.Data.GetCommands(common.DevfileOptions{})
+	if err != nil {
+		t.Fatalf("GetCommands() error = %v", err)
+	}
+	if len(commands) != 1 || commands[0].Id != "build" {
+		t.Errorf("after Fix(), commands = %+v, want a single command with id %q", commands, "build")
+	}
+}
@@ -0,0 +1,49 @@
+// Package consumer lets IDE integrations subscribe to the NDJSON
+// DevfileEvent stream odo writes to stdout for `odo push`/`odo log`/
+// `odo exec` (see pkg/machineoutput.DevfileEventLoggingClient), without
+// each integration having to hand-roll its own line-oriented JSON decoder.
+package consumer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/openshift/odo/pkg/machineoutput"
+)
+
+// Handler is called once per DevfileEvent line as it is decoded. Returning
+// an error stops Subscribe and is returned to its caller.
+type Handler func(event machineoutput.DevfileEvent) error
+
+// Subscribe reads NDJSON DevfileEvent lines from r until EOF or handler
+// returns an error, calling handler for each one in order. Lines that
+// aren't valid DevfileEvent JSON (e.g. because odo also wrote a
+// human-readable warning to the same stream) are skipped rather than
+// treated as fatal, since the event stream is additive to existing output.
+func Subscribe(r io.Reader, handler Handler) error {
+	scanner := bufio.NewScanner(r)
+	// devfile events, particularly CommandLogLine, can carry long build
+	// output lines; grow past bufio.Scanner's 64KB default.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var event machineoutput.DevfileEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			continue
+		}
+		if event.Kind != machineoutput.DevfileEventKind {
+			continue
+		}
+
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,75 @@
+package consumer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/odo/pkg/machineoutput"
+)
+
+func TestSubscribe_DecodesEventsInOrder(t *testing.T) {
+	const stream = `{"apiVersion":"odo.dev/v1","kind":"DevfileEvent","type":"CommandStarted","ts":"","component":"app","data":{"command":"push"}}
+{"apiVersion":"odo.dev/v1","kind":"DevfileEvent","type":"CommandCompleted","ts":"","component":"app","data":{"command":"push","success":true}}
+`
+
+	var types []machineoutput.DevfileEventType
+	err := Subscribe(strings.NewReader(stream), func(event machineoutput.DevfileEvent) error {
+		types = append(types, event.Type)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	want := []machineoutput.DevfileEventType{machineoutput.DevfileEventCommandStarted, machineoutput.DevfileEventCommandCompleted}
+	if len(types) != len(want) {
+		t.Fatalf("Subscribe() delivered %d events, want %d", len(types), len(want))
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("event %d type = %q, want %q", i, types[i], want[i])
+		}
+	}
+}
+
+func TestSubscribe_SkipsNonDevfileEventLines(t *testing.T) {
+	const stream = `not json at all
+{"kind":"SomethingElse"}
+{"apiVersion":"odo.dev/v1","kind":"DevfileEvent","type":"CommandStarted","ts":"","component":"app","data":{"command":"push"}}
+`
+
+	var count int
+	err := Subscribe(strings.NewReader(stream), func(event machineoutput.DevfileEvent) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Subscribe() delivered %d events, want 1", count)
+	}
+}
+
+func TestSubscribe_StopsOnHandlerError(t *testing.T) {
+	const stream = `{"apiVersion":"odo.dev/v1","kind":"DevfileEvent","type":"CommandStarted","ts":"","component":"app","data":{"command":"push"}}
+{"apiVersion":"odo.dev/v1","kind":"DevfileEvent","type":"CommandCompleted","ts":"","component":"app","data":{"command":"push"}}
+`
+	wantErr := errStop{}
+
+	var count int
+	err := Subscribe(strings.NewReader(stream), func(event machineoutput.DevfileEvent) error {
+		count++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("Subscribe() error = %v, want %v", err, wantErr)
+	}
+	if count != 1 {
+		t.Errorf("Subscribe() called handler %d times after it returned an error, want 1", count)
+	}
+}
+
+type errStop struct{}
+
+func (errStop) Error() string { return "stop" }
@@ -0,0 +1,127 @@
+package machineoutput
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// DevfileEventAPIVersion is the schema version stamped on every event
+// emitted by NewDevfileEventLoggingClient. Bump it whenever a field is
+// removed or its meaning changes; consumers can safely ignore fields they
+// don't recognize within the same major version.
+const DevfileEventAPIVersion = "odo.dev/v1"
+
+// DevfileEventKind is always "DevfileEvent"; it exists alongside Type so
+// consumers can tell a devfile push/log/exec event apart from any other
+// NDJSON object odo might one day emit on the same stream.
+const DevfileEventKind = "DevfileEvent"
+
+// DevfileEventType identifies which stage of devfilePushInner, DevfileComponentLog
+// or DevfileComponentExec produced an event.
+type DevfileEventType string
+
+const (
+	DevfileEventCommandStarted   DevfileEventType = "CommandStarted"
+	DevfileEventCommandLogLine   DevfileEventType = "CommandLogLine"
+	DevfileEventSyncProgress     DevfileEventType = "SyncProgress"
+	DevfileEventURLReady         DevfileEventType = "URLReady"
+	DevfileEventCommandCompleted DevfileEventType = "CommandCompleted"
+)
+
+// DevfileEvent is one line of the NDJSON stream odo writes to stdout for
+// `odo push`/`odo log`/`odo exec` when log.IsJSON() is true. Each line is a
+// complete, independently-parseable JSON object; there is no enclosing
+// array so a consumer can start rendering before the command finishes.
+type DevfileEvent struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Type       DevfileEventType `json:"type"`
+	Timestamp  string           `json:"ts"`
+	Component  string           `json:"component,omitempty"`
+	Data       interface{}      `json:"data"`
+}
+
+// SyncProgressData is the Data payload of a SyncProgress event, letting IDE
+// integrations render a progress bar for the file sync odo performs as
+// part of Push.
+type SyncProgressData struct {
+	BytesTransferred int64  `json:"bytesTransferred"`
+	BytesTotal       int64  `json:"bytesTotal"`
+	CurrentFile      string `json:"currentFile,omitempty"`
+}
+
+// CommandData is the Data payload of CommandStarted/CommandCompleted
+// events.
+type CommandData struct {
+	Command string `json:"command"`
+	Success bool   `json:"success,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CommandLogLineData is the Data payload of a CommandLogLine event.
+type CommandLogLineData struct {
+	Command string `json:"command"`
+	Line    string `json:"line"`
+	Stream  string `json:"stream"` // "stdout" or "stderr"
+}
+
+// URLReadyData is the Data payload of a URLReady event.
+type URLReadyData struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// DevfileEventLoggingClient writes DevfileEvent NDJSON lines to an
+// io.Writer (normally os.Stdout), one per call, so IDE integrations can
+// subscribe to push/log/exec progress instead of scraping human-readable
+// output. Emit stamps each event with TimestampNow() at the time it is
+// written.
+//
+// Every event a client emits is stamped with the component name it was
+// constructed with, so a consumer watching several components push at
+// once (e.g. `odo push --all`) can demultiplex the interleaved NDJSON
+// lines on the same stdout stream.
+type DevfileEventLoggingClient struct {
+	w         io.Writer
+	component string
+}
+
+// emitMu serializes every DevfileEventLoggingClient's Emit call, regardless
+// of which client or goroutine it's called from. `odo push --all` pushes
+// several components concurrently, each with its own client wrapping the
+// same os.Stdout, and a CommandLogLine event can carry an arbitrarily long
+// build-output line; without a shared lock two pushes' Write calls can
+// interleave mid-line and corrupt both NDJSON lines.
+var emitMu sync.Mutex
+
+// NewDevfileEventLoggingClient wraps w (typically os.Stdout) in a
+// DevfileEventLoggingClient that stamps every event it emits with
+// component.
+func NewDevfileEventLoggingClient(w io.Writer, component string) DevfileEventLoggingClient {
+	return DevfileEventLoggingClient{w: w, component: component}
+}
+
+// Emit writes a single DevfileEvent line. Errors are deliberately not
+// returned: a failure to write a progress event should never fail the
+// underlying push/log/exec command.
+func (c DevfileEventLoggingClient) Emit(eventType DevfileEventType, data interface{}) {
+	event := DevfileEvent{
+		APIVersion: DevfileEventAPIVersion,
+		Kind:       DevfileEventKind,
+		Type:       eventType,
+		Timestamp:  TimestampNow(),
+		Component:  c.component,
+		Data:       data,
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	emitMu.Lock()
+	defer emitMu.Unlock()
+	_, _ = c.w.Write(line)
+}
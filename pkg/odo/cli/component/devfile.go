@@ -1,6 +1,10 @@
 package component
 
 import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -8,19 +12,25 @@ import (
 
 	devfilev1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/devfile/library/pkg/devfile"
+	"github.com/devfile/library/pkg/devfile/parser"
+	"github.com/openshift/odo/pkg/devfile/auth"
+	"github.com/openshift/odo/pkg/devfile/cache"
 	"github.com/openshift/odo/pkg/devfile/validate"
 	"github.com/openshift/odo/pkg/envinfo"
 	"github.com/openshift/odo/pkg/localConfigProvider"
 	"github.com/openshift/odo/pkg/machineoutput"
 	"github.com/openshift/odo/pkg/odo/genericclioptions"
 	"github.com/openshift/odo/pkg/odo/util/pushtarget"
+	"github.com/openshift/odo/pkg/preference"
 	"github.com/openshift/odo/pkg/util"
 	"github.com/pkg/errors"
 
 	"github.com/openshift/odo/pkg/devfile/adapters"
 	"github.com/openshift/odo/pkg/devfile/adapters/common"
 	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes"
+	"github.com/openshift/odo/pkg/devfile/adapters/podman"
 	"github.com/openshift/odo/pkg/log"
+	"github.com/spf13/cobra"
 )
 
 /*
@@ -49,6 +59,245 @@ const (
 // 3. Copy user's own devfile (path is specified via --devfile flag) to DevfilePath then use the devfile in DevfilePath
 var DevfilePath = filepath.Join(LocalDirectoryDefaultLocation, devFile)
 
+// AddTokenFlag registers the --token flag shared by every devfile-aware
+// command (push/log/exec/delete), binding it to token so
+// parseAndValidateDevfile picks it up ahead of the keyring/env var/
+// preference.yaml lookup. Call it from each command's NewCmdX alongside
+// its other flags.
+func AddTokenFlag(cmd *cobra.Command, token *string) {
+	cmd.Flags().StringVar(token, "token", "", "token for fetching a devfile (or its parent) from a private git provider or devfile registry; saved to the OS keyring for reuse")
+}
+
+// AddDevfileUpdateLockFlag registers the --devfile-update-lock flag on
+// push, binding it to updateLock so parseAndValidateDevfile re-pins
+// devfileLock to a parent devfile that changed upstream instead of
+// treating the change as a mismatch.
+func AddDevfileUpdateLockFlag(cmd *cobra.Command, updateLock *bool) {
+	cmd.Flags().BoolVar(updateLock, "devfile-update-lock", false, "accept a parent devfile that changed upstream and re-pin devfileLock to it")
+}
+
+// parseAndValidateDevfile parses the devfile (and, transitively, any parent
+// or plugin devfile it references) at devfilePath, resolving a token for
+// the request the same way for every entry point in this file: an explicit
+// --token flag wins, otherwise the keyring/ODO_DEVFILE_TOKEN/preference.yaml
+// lookup in pkg/devfile/auth is used, keyed by tokenSource(devfilePath) -
+// devfilePath itself when it's already a URL (the registry-fetch case), or
+// the URI of the parent devfile it references when devfilePath is a local
+// file, since the parent is what's actually fetched over the network and
+// may live in a different private repo than wherever devfilePath came
+// from. This lets `odo push`/`log`/`exec`/`delete` fetch devfiles (and
+// parents) that live in private Git providers or private devfile
+// registries instead of failing silently at parse time.
+//
+// When the devfile has a parent, the fully-flattened result (parent
+// overrides merged) is cached under ~/.odo/devfile-cache and its content
+// hash is pinned as envInfo's devfileLock, so that a subsequent invocation
+// can run offline against the same resolved devfile, and so that a parent
+// that silently changed upstream is caught instead of being merged in
+// unnoticed. Pass updateLock=true (--devfile-update-lock) to accept a
+// changed parent and re-pin the lock. A devfile with no parent has nothing
+// to pin or verify and skips this entirely, so editing it directly never
+// trips devfileLock.
+func parseAndValidateDevfile(devfilePath, token string, envInfo *envinfo.EnvSpecificInfo, updateLock bool) (parser.DevfileObj, error) {
+	prefClient, err := preference.NewClient()
+	if err != nil {
+		return parser.DevfileObj{}, errors.Wrap(err, "unable to load preference settings")
+	}
+
+	source := tokenSource(devfilePath)
+
+	resolvedToken := token
+	if resolvedToken == "" {
+		resolvedToken, err = auth.Resolve(source, prefClient.DevfileRegistryTokens())
+		if err != nil {
+			return parser.DevfileObj{}, errors.Wrap(err, "unable to resolve devfile token")
+		}
+	} else if err := auth.Save(source, resolvedToken); err != nil {
+		// Non-fatal: an explicit --token still works for this invocation
+		// even if we can't persist it for the next one (e.g. no keyring
+		// backend is available in this environment).
+		log.Warningf("Unable to save --token to the keyring for reuse: %v", err)
+	}
+
+	args := parser.ParserArgs{
+		Path: devfilePath,
+	}
+	if resolvedToken != "" {
+		args.Token = resolvedToken
+		args.GitProvider = string(auth.DetectProvider(source))
+	}
+
+	lockedHash := envInfo.GetDevfileLock()
+
+	devObj, err := devfile.ParseAndValidateWithArgs(args)
+	if err != nil {
+		// The parent (or the devfile itself) couldn't be resolved live, e.g.
+		// because we're offline or air-gapped. Fall back to the last
+		// flattened devfile we pinned, if we have one.
+		if lockedHash == "" || !cache.Has(lockedHash) {
+			return parser.DevfileObj{}, err
+		}
+		content, cacheErr := cache.Load(lockedHash)
+		if cacheErr != nil {
+			return parser.DevfileObj{}, err
+		}
+		log.Warningf("Unable to resolve devfile parent (%v); using the cached devfile pinned by devfileLock %s", err, lockedHash)
+		return parser.ParseFromDataAndValidate(content)
+	}
+
+	if devObj.Data.GetParent() == nil {
+		// Nothing was merged in from upstream, so there's nothing to pin
+		// or verify against: a devfile with no parent can only have
+		// changed because its author edited it directly, and that should
+		// never trip devfileLock. Skip the cache/lock dance entirely so a
+		// plain edit-devfile-then-push never hits ErrLockMismatch.
+		return devObj, nil
+	}
+
+	content, err := devObj.Data.Marshal()
+	if err != nil {
+		return parser.DevfileObj{}, errors.Wrap(err, "unable to serialize resolved devfile for caching")
+	}
+
+	hash, err := cache.Verify(content, lockedHash, updateLock)
+	if err != nil {
+		return parser.DevfileObj{}, err
+	}
+
+	if _, err := cache.Store(content); err != nil {
+		return parser.DevfileObj{}, err
+	}
+
+	if hash != lockedHash {
+		if err := envInfo.SetDevfileLock(hash); err != nil {
+			return parser.DevfileObj{}, errors.Wrap(err, "unable to persist devfileLock")
+		}
+	}
+
+	return devObj, nil
+}
+
+// tokenSource returns the URL auth.Resolve/Save/DetectProvider should key
+// a token lookup on for parsing devfilePath: devfilePath itself when it's
+// already a URL (the registry-fetch case, where devfilePath is what gets
+// fetched), or the URI of the parent devfile reference it contains when
+// devfilePath is a local file, since that parent - which may live in a
+// completely different private repo - is the thing actually fetched over
+// the network. A local devfile with no parent, or a parent referenced by
+// registry id/registryUrl rather than a git uri, falls back to
+// devfilePath; DetectProvider will return ProviderUnknown for it and the
+// only way it picks up a token at all is an explicit --token or a
+// preference.yaml entry keyed on the local path, same as before.
+func tokenSource(devfilePath string) string {
+	if strings.Contains(devfilePath, "://") {
+		return devfilePath
+	}
+
+	uri, err := parentURIFromFile(devfilePath)
+	if err != nil || uri == "" {
+		return devfilePath
+	}
+	return uri
+}
+
+// parentURIFromFile does a best-effort raw scan of a local devfile.yaml's
+// top-level "parent:" block for its "uri:" field, without invoking the
+// full devfile parser - which is exactly what we're trying to resolve a
+// token ahead of. It returns ("", nil) if the file has no parent, or a
+// parent referenced by registry id/registryUrl rather than a plain git
+// uri.
+func parentURIFromFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	inParent := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "parent:":
+			inParent = true
+		case inParent && trimmed != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t"):
+			// Dedented back to the top level: the parent block ended
+			// without a uri.
+			return "", nil
+		case inParent && strings.HasPrefix(trimmed, "uri:"):
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "uri:")), `"'`), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// resolvePlatformContext picks the platformContext adapters.NewComponentAdapter
+// expects, based on the push target selected via `--push-target`/the
+// `pushtarget` preference key. It centralizes what used to be a
+// pushtarget.IsPushTargetDocker() branch repeated at every call site in
+// this file, now that podman is a third supported backend alongside
+// kubernetes and docker.
+func resolvePlatformContext(namespace string) interface{} {
+	switch pushtarget.Get() {
+	case pushtarget.KindDocker:
+		return nil
+	case pushtarget.KindPodman:
+		return podman.PlatformContext{}
+	default:
+		return kubernetes.KubernetesContext{Namespace: namespace}
+	}
+}
+
+// newDevfileEventClient returns a client that streams versioned NDJSON
+// DevfileEvent lines to stdout for IDE integrations (see
+// pkg/machineoutput/consumer), and whether streaming is actually enabled.
+// Streaming only happens in JSON mode; the human-readable log.* output
+// elsewhere in this file remains the default. Every event the returned
+// client emits is stamped with componentName, so a consumer can tell
+// several concurrently-pushing components' interleaved NDJSON lines apart
+// (see DevfilePushAll).
+func newDevfileEventClient(componentName string) (machineoutput.DevfileEventLoggingClient, bool) {
+	if !log.IsJSON() {
+		return machineoutput.DevfileEventLoggingClient{}, false
+	}
+	return machineoutput.NewDevfileEventLoggingClient(os.Stdout, componentName), true
+}
+
+// streamDevfileLog forwards rd, the log output of command, as
+// CommandLogLine events instead of the human-readable path DisplayLog
+// takes, closing rd and emitting CommandCompleted once it's exhausted.
+func streamDevfileLog(events machineoutput.DevfileEventLoggingClient, command string, rd io.ReadCloser) error {
+	defer rd.Close()
+
+	events.Emit(machineoutput.DevfileEventCommandStarted, machineoutput.CommandData{Command: command})
+
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		events.Emit(machineoutput.DevfileEventCommandLogLine, machineoutput.CommandLogLineData{
+			Command: command,
+			Line:    scanner.Text(),
+			Stream:  "stdout",
+		})
+	}
+
+	err := scanner.Err()
+	events.Emit(machineoutput.DevfileEventCommandCompleted, machineoutput.CommandData{
+		Command: command,
+		Success: err == nil,
+		Error:   errString(err),
+	})
+	return err
+}
+
+// errString returns err.Error(), or "" if err is nil, so it can be embedded
+// directly in a CommandData event without every call site nil-checking.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
 // DevfilePush has the logic to perform the required actions for a given devfile
 func (po *PushOptions) DevfilePush() error {
 
@@ -56,12 +305,15 @@ func (po *PushOptions) DevfilePush() error {
 	err := po.devfilePushInner()
 
 	if err != nil && log.IsJSON() {
-		eventLoggingClient := machineoutput.NewConsoleMachineEventLoggingClient()
-		eventLoggingClient.ReportError(err, machineoutput.TimestampNow())
-
-		// Suppress the error to prevent it from being output by the generic machine-readable handler (which will produce invalid JSON for our purposes)
-		err = nil
-
+		// devfilePushInner emits a CommandCompleted NDJSON event carrying
+		// this failure for every failure path, including ones (parse,
+		// validate, auth, cache-lock) that happen before pushDevfile ever
+		// runs - see reportEarlyPushFailure. So the NDJSON stream is the
+		// sole error-reporting mechanism for devfile push in JSON mode;
+		// don't also run it through ConsoleMachineEventLoggingClient,
+		// which would write a second, differently-shaped JSON payload for
+		// the same failure onto the same stdout stream.
+		//
 		// os.Exit(1) since we are suppressing the generic machine-readable handler's exit code logic
 		os.Exit(1)
 	}
@@ -80,21 +332,63 @@ func (po *PushOptions) DevfilePush() error {
 }
 
 func (po *PushOptions) devfilePushInner() (err error) {
+	componentName := po.EnvSpecificInfo.GetName()
 
 	// Parse devfile and validate
-	devObj, err := devfile.ParseAndValidate(po.DevfilePath)
-
+	devObj, err := parseAndValidateDevfile(po.DevfilePath, po.token, po.EnvSpecificInfo, po.devfileUpdateLock)
 	if err != nil {
+		reportEarlyPushFailure(componentName, err)
 		return err
 	}
 
 	err = validate.ValidateDevfileData(devObj.Data)
 	if err != nil {
+		reportEarlyPushFailure(componentName, err)
 		return err
 	}
 
+	return po.pushDevfile(devObj)
+}
+
+// reportEarlyPushFailure emits the CommandStarted/CommandCompleted "push"
+// NDJSON event pair for a failure that happens before pushDevfile runs
+// (devfile parsing, schema validation, auth, cache-lock). pushDevfile
+// emits this same pair for failures in or after it, so without this an
+// error here would leave JSON-mode consumers watching stdout with no
+// event at all before the process exits.
+func reportEarlyPushFailure(componentName string, err error) {
+	events, streaming := newDevfileEventClient(componentName)
+	if !streaming {
+		return
+	}
+	events.Emit(machineoutput.DevfileEventCommandStarted, machineoutput.CommandData{Command: "push"})
+	events.Emit(machineoutput.DevfileEventCommandCompleted, machineoutput.CommandData{
+		Command: "push",
+		Success: false,
+		Error:   errString(err),
+	})
+}
+
+// pushDevfile does the actual work of starting or updating a component from
+// an already-parsed and validated devObj. It is split out of
+// devfilePushInner so that DevfilePushAll's planner phase can parse every
+// workspace component up front (and short-circuit before spawning any
+// worker) and then reuse this exact single-component logic concurrently.
+func (po *PushOptions) pushDevfile(devObj parser.DevfileObj) (err error) {
 	componentName := po.EnvSpecificInfo.GetName()
 
+	events, streaming := newDevfileEventClient(componentName)
+	if streaming {
+		events.Emit(machineoutput.DevfileEventCommandStarted, machineoutput.CommandData{Command: "push"})
+		defer func() {
+			events.Emit(machineoutput.DevfileEventCommandCompleted, machineoutput.CommandData{
+				Command: "push",
+				Success: err == nil,
+				Error:   errString(err),
+			})
+		}()
+	}
+
 	// Set the source path to either the context or current working directory (if context not set)
 	po.sourcePath, err = util.GetAbsPath(po.componentContext)
 	if err != nil {
@@ -107,15 +401,7 @@ func (po *PushOptions) devfilePushInner() (err error) {
 		return errors.Wrap(err, "unable to apply ignore information")
 	}
 
-	var platformContext interface{}
-	if pushtarget.IsPushTargetDocker() {
-		platformContext = nil
-	} else {
-		kc := kubernetes.KubernetesContext{
-			Namespace: po.KClient.Namespace,
-		}
-		platformContext = kc
-	}
+	platformContext := resolvePlatformContext(po.KClient.Namespace)
 
 	devfileHandler, err := adapters.NewComponentAdapter(componentName, po.componentContext, po.Application, devObj, platformContext)
 	if err != nil {
@@ -135,6 +421,16 @@ func (po *PushOptions) devfilePushInner() (err error) {
 		DebugPort:       po.EnvSpecificInfo.GetDebugPort(),
 	}
 
+	if streaming {
+		pushParams.ProgressReporter = func(bytesTransferred, bytesTotal int64, currentFile string) {
+			events.Emit(machineoutput.DevfileEventSyncProgress, machineoutput.SyncProgressData{
+				BytesTransferred: bytesTransferred,
+				BytesTotal:       bytesTotal,
+				CurrentFile:      currentFile,
+			})
+		}
+	}
+
 	localURLs, err := po.EnvSpecificInfo.ListURLs()
 	if err != nil {
 		return err
@@ -151,6 +447,11 @@ func (po *PushOptions) devfilePushInner() (err error) {
 	} else {
 		log.Infof("\nPushing devfile component %s", componentName)
 		log.Success("Changes successfully pushed to component")
+		if streaming {
+			for _, u := range localURLs {
+				events.Emit(machineoutput.DevfileEventURLReady, machineoutput.URLReadyData{Name: u.Name, URL: u.Host})
+			}
+		}
 	}
 
 	return
@@ -159,7 +460,7 @@ func (po *PushOptions) devfilePushInner() (err error) {
 // DevfileComponentLog fetch and display log from devfile components
 func (lo LogOptions) DevfileComponentLog() error {
 	// Parse devfile
-	devObj, err := devfile.ParseAndValidate(lo.devfilePath)
+	devObj, err := parseAndValidateDevfile(lo.devfilePath, lo.token, lo.Context.EnvSpecificInfo, false)
 	if err != nil {
 		return err
 	}
@@ -169,15 +470,7 @@ func (lo LogOptions) DevfileComponentLog() error {
 	}
 	componentName := lo.Context.EnvSpecificInfo.GetName()
 
-	var platformContext interface{}
-	if pushtarget.IsPushTargetDocker() {
-		platformContext = nil
-	} else {
-		kc := kubernetes.KubernetesContext{
-			Namespace: lo.KClient.Namespace,
-		}
-		platformContext = kc
-	}
+	platformContext := resolvePlatformContext(lo.KClient.Namespace)
 
 	devfileHandler, err := adapters.NewComponentAdapter(componentName, lo.componentContext, lo.Application, devObj, platformContext)
 
@@ -213,13 +506,17 @@ func (lo LogOptions) DevfileComponentLog() error {
 		os.Exit(1)
 	}
 
+	if events, streaming := newDevfileEventClient(componentName); streaming {
+		return streamDevfileLog(events, command.Id, rd)
+	}
+
 	return util.DisplayLog(lo.logFollow, rd, os.Stdout, componentName, -1)
 }
 
 // DevfileComponentDelete deletes the devfile component
 func (do *DeleteOptions) DevfileComponentDelete() error {
 	// Parse devfile and validate
-	devObj, err := devfile.ParseAndValidate(do.devfilePath)
+	devObj, err := parseAndValidateDevfile(do.devfilePath, do.token, do.EnvSpecificInfo, false)
 	if err != nil {
 		return err
 	}
@@ -229,14 +526,12 @@ func (do *DeleteOptions) DevfileComponentDelete() error {
 	}
 	componentName := do.EnvSpecificInfo.GetName()
 
-	kc := kubernetes.KubernetesContext{
-		Namespace: do.namespace,
-	}
+	platformContext := resolvePlatformContext(do.namespace)
 
 	labels := map[string]string{
 		"component": componentName,
 	}
-	devfileHandler, err := adapters.NewComponentAdapter(componentName, do.componentContext, do.Application, devObj, kc)
+	devfileHandler, err := adapters.NewComponentAdapter(componentName, do.componentContext, do.Application, devObj, platformContext)
 	if err != nil {
 		return err
 	}
@@ -248,15 +543,7 @@ func (do *DeleteOptions) DevfileComponentDelete() error {
 func (to *TestOptions) RunTestCommand() error {
 	componentName := to.Context.EnvSpecificInfo.GetName()
 
-	var platformContext interface{}
-	if pushtarget.IsPushTargetDocker() {
-		platformContext = nil
-	} else {
-		kc := kubernetes.KubernetesContext{
-			Namespace: to.KClient.Namespace,
-		}
-		platformContext = kc
-	}
+	platformContext := resolvePlatformContext(to.KClient.Namespace)
 
 	devfileHandler, err := adapters.NewComponentAdapter(componentName, to.componentContext, to.Application, to.devObj, platformContext)
 	if err != nil {
@@ -293,7 +580,7 @@ func warnIfURLSInvalid(url []localConfigProvider.LocalURL) {
 // DevfileComponentExec executes the given user command inside the component
 func (eo *ExecOptions) DevfileComponentExec(command []string) error {
 	// Parse devfile
-	devObj, err := devfile.ParseAndValidate(eo.devfilePath)
+	devObj, err := parseAndValidateDevfile(eo.devfilePath, eo.token, eo.componentOptions.EnvSpecificInfo, false)
 	if err != nil {
 		return err
 	}
@@ -304,14 +591,26 @@ func (eo *ExecOptions) DevfileComponentExec(command []string) error {
 
 	componentName := eo.componentOptions.EnvSpecificInfo.GetName()
 
-	kc := kubernetes.KubernetesContext{
-		Namespace: eo.namespace,
-	}
+	platformContext := resolvePlatformContext(eo.namespace)
 
-	devfileHandler, err := adapters.NewComponentAdapter(componentName, eo.componentContext, eo.componentOptions.Application, devObj, kc)
+	devfileHandler, err := adapters.NewComponentAdapter(componentName, eo.componentContext, eo.componentOptions.Application, devObj, platformContext)
 	if err != nil {
 		return err
 	}
 
-	return devfileHandler.Exec(command)
+	commandLine := strings.Join(command, " ")
+	events, streaming := newDevfileEventClient(componentName)
+	if streaming {
+		events.Emit(machineoutput.DevfileEventCommandStarted, machineoutput.CommandData{Command: commandLine})
+	}
+
+	err = devfileHandler.Exec(command)
+	if streaming {
+		events.Emit(machineoutput.DevfileEventCommandCompleted, machineoutput.CommandData{
+			Command: commandLine,
+			Success: err == nil,
+			Error:   errString(err),
+		})
+	}
+	return err
 }
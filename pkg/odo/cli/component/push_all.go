@@ -0,0 +1,194 @@
+package component
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/devfile/library/pkg/devfile/parser"
+	"github.com/openshift/odo/pkg/devfile/validate"
+	"github.com/openshift/odo/pkg/envinfo"
+	"github.com/openshift/odo/pkg/log"
+	"github.com/openshift/odo/pkg/workspace"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// maxConcurrentPushes bounds how many components DevfilePushAll pushes at
+// once, so `odo push --all` on a large workspace doesn't try to open a
+// build/exec connection per component all at the same time.
+const maxConcurrentPushes = 4
+
+// AddAllFlag registers the --all flag on push, binding it to all so
+// RunPush can choose between pushing just the current component and
+// pushing every component declared in odo-workspace.yaml.
+func AddAllFlag(cmd *cobra.Command, all *bool) {
+	cmd.Flags().BoolVar(all, "all", false, "push every devfile component declared in odo-workspace.yaml instead of just this one")
+}
+
+// RunPush is the single entry point `odo push`'s RunE should call: it picks
+// between DevfilePush and DevfilePushAll based on --all, the same way
+// devfilePushInner and DevfilePushAll share pushDevfile for the actual
+// single-component push logic.
+func (po *PushOptions) RunPush(all bool) error {
+	if all {
+		return po.DevfilePushAll()
+	}
+	return po.DevfilePush()
+}
+
+// pushPlan is the result of the planner phase for a single workspace
+// component: its own *PushOptions (scoped to its own directory and its own
+// env.yaml) and its already-parsed, already-validated devfile.
+type pushPlan struct {
+	spec workspace.ComponentSpec
+	po   *PushOptions
+	dev  parser.DevfileObj
+}
+
+// DevfilePushAll discovers the devfile-based components declared in
+// odo-workspace.yaml, orders them by their declared dependsOn relationships,
+// and pushes independent components concurrently. Every component reuses
+// the exact single-component push logic devfilePushInner uses (via
+// pushDevfile); a component whose dependency failed to push is skipped
+// rather than attempted.
+func (po *PushOptions) DevfilePushAll() error {
+	ws, err := workspace.Load(po.componentContext)
+	if err != nil {
+		return errors.Wrap(err, "unable to load odo-workspace.yaml")
+	}
+
+	batches, err := ws.Plan()
+	if err != nil {
+		return err
+	}
+
+	// Planner phase: create per-component PushOptions and parse/validate
+	// every component's devfile up front, so a bad devfile anywhere in the
+	// workspace fails fast instead of after other components have already
+	// started pushing.
+	plans := make(map[string]*pushPlan, len(ws.Components))
+	for _, batch := range batches {
+		for _, spec := range batch {
+			plan, planErr := po.planComponentPush(spec)
+			if planErr != nil {
+				return errors.Wrapf(planErr, "unable to prepare component %q for push", spec.Name)
+			}
+			plans[spec.Name] = plan
+		}
+	}
+
+	failed := map[string]bool{}
+	for _, batch := range batches {
+		runBatch(batch, plans, failed)
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("%d of %d components failed to push", len(failed), len(ws.Components))
+	}
+	return nil
+}
+
+// planComponentPush hoists PushOptions creation and devfile parsing for a
+// single workspace component out of the concurrent execution phase.
+func (po *PushOptions) planComponentPush(spec workspace.ComponentSpec) (*pushPlan, error) {
+	componentPo, err := po.forWorkspaceComponent(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	devObj, err := parseAndValidateDevfile(componentPo.DevfilePath, componentPo.token, componentPo.EnvSpecificInfo, componentPo.devfileUpdateLock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validate.ValidateDevfileData(devObj.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pushPlan{spec: spec, po: componentPo, dev: devObj}, nil
+}
+
+// forWorkspaceComponent clones po's shared flags (force build, debug, ignore
+// patterns, ...) but scopes componentContext, DevfilePath and
+// EnvSpecificInfo to spec's own directory, since each workspace component
+// has its own devfile and env.yaml.
+func (po *PushOptions) forWorkspaceComponent(spec workspace.ComponentSpec) (*PushOptions, error) {
+	clone := *po
+	clone.componentContext = filepath.Join(po.componentContext, spec.Path)
+	clone.DevfilePath = filepath.Join(clone.componentContext, devFile)
+
+	envInfo, err := envinfo.NewEnvSpecificInfo(clone.componentContext)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to load env.yaml for component %q", spec.Name)
+	}
+	clone.EnvSpecificInfo = envInfo
+
+	return &clone, nil
+}
+
+// runBatch pushes every component in a dependency batch concurrently,
+// bounded by maxConcurrentPushes, and records failures (including
+// components skipped because a dependency already failed) into failed.
+func runBatch(batch []workspace.ComponentSpec, plans map[string]*pushPlan, failed map[string]bool) {
+	sem := make(chan struct{}, maxConcurrentPushes)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for _, spec := range batch {
+		mu.Lock()
+		skip := dependsOnFailed(spec, failed)
+		if skip {
+			failed[spec.Name] = true
+		}
+		mu.Unlock()
+		if skip {
+			log.Warningf("Skipping component %q because a dependency failed to push", spec.Name)
+			continue
+		}
+
+		plan := plans[spec.Name]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(plan *pushPlan) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := plan.po.pushDevfile(plan.dev); err != nil {
+				mu.Lock()
+				failed[plan.spec.Name] = true
+				mu.Unlock()
+				log.Errorf("Failed to push component %q: %v", plan.spec.Name, err)
+				return
+			}
+
+			// Mirror DevfilePush: persist the run mode used so a later
+			// `odo log`/`odo exec` on this component agrees with how it
+			// was actually last pushed.
+			runMode := envinfo.Run
+			if plan.po.debugRun {
+				runMode = envinfo.Debug
+			}
+			if err := plan.po.EnvSpecificInfo.SetRunMode(runMode); err != nil {
+				mu.Lock()
+				failed[plan.spec.Name] = true
+				mu.Unlock()
+				log.Errorf("Component %q pushed but failed to persist its run mode: %v", plan.spec.Name, err)
+				return
+			}
+
+			log.Successf("Component %q pushed", plan.spec.Name)
+		}(plan)
+	}
+
+	wg.Wait()
+}
+
+func dependsOnFailed(spec workspace.ComponentSpec, failed map[string]bool) bool {
+	for _, dep := range spec.DependsOn {
+		if failed[dep] {
+			return true
+		}
+	}
+	return false
+}
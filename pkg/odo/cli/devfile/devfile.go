@@ -0,0 +1,25 @@
+package devfile
+
+import (
+	"github.com/openshift/odo/pkg/odo/util"
+	"github.com/spf13/cobra"
+)
+
+// RecommendedCommandName is the recommended devfile command name.
+const RecommendedCommandName = "devfile"
+
+// NewCmdDevfile implements the `odo devfile` command group. Register the
+// result with the root command via rootCmd.AddCommand, the same way every
+// other top-level command group is added.
+func NewCmdDevfile(name, fullName string) *cobra.Command {
+	lintCmd := NewCmdLint(RecommendedLintCommandName, util.GetFullName(fullName, RecommendedLintCommandName))
+
+	devfileCmd := &cobra.Command{
+		Use:   name,
+		Short: "Perform devfile operations that don't require a running component",
+	}
+	devfileCmd.AddCommand(lintCmd)
+	util.SetCommandGroup(devfileCmd, util.MainGroup)
+
+	return devfileCmd
+}
@@ -0,0 +1,116 @@
+// Package devfile holds the `odo devfile` command group: subcommands that
+// operate on a devfile.yaml directly rather than on a running component.
+package devfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/devfile/library/pkg/devfile"
+	"github.com/openshift/odo/pkg/devfile/validate"
+	"github.com/openshift/odo/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// RecommendedLintCommandName is the recommended lint command name.
+const RecommendedLintCommandName = "lint"
+
+// defaultDevfilePath is used when --devfile isn't passed.
+const defaultDevfilePath = "devfile.yaml"
+
+// LintOptions encapsulates the options for the `odo devfile lint` command.
+type LintOptions struct {
+	// devfilePath is the devfile.yaml to lint.
+	devfilePath string
+	// fix rewrites devfilePath in place for every diagnostic that has a
+	// safe autofix.
+	fix bool
+}
+
+// NewLintOptions creates a new LintOptions instance.
+func NewLintOptions() *LintOptions {
+	return &LintOptions{}
+}
+
+// Run parses devfilePath, runs every registered lint rule against it, and
+// prints the resulting diagnostics. With --fix, it also applies every
+// diagnostic's autofix (if any) and rewrites devfilePath in place.
+func (o *LintOptions) Run() error {
+	content, err := ioutil.ReadFile(o.devfilePath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read %s", o.devfilePath)
+	}
+
+	devObj, err := devfile.ParseAndValidate(o.devfilePath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse %s", o.devfilePath)
+	}
+
+	diagnostics := validate.Lint(devObj, content)
+	if len(diagnostics) == 0 {
+		log.Success("No issues found")
+		return nil
+	}
+
+	sourceLines := strings.Split(string(content), "\n")
+	for _, d := range diagnostics {
+		fmt.Printf("[%s] %s %s: %s\n", d.Severity, o.devfilePath, d.Location, d.Message)
+		if d.Line > 0 && d.Line <= len(sourceLines) {
+			fmt.Printf("    %d | %s\n", d.Line, strings.TrimRight(sourceLines[d.Line-1], "\r"))
+		}
+	}
+
+	if !o.fix {
+		return errors.Errorf("%d issue(s) found; rerun with --fix to apply safe autofixes", len(diagnostics))
+	}
+
+	fixed := 0
+	for _, d := range diagnostics {
+		if d.Fix == nil {
+			continue
+		}
+		if err := d.Fix(&devObj); err != nil {
+			return errors.Wrapf(err, "unable to apply fix for rule %s", d.Rule)
+		}
+		fixed++
+	}
+
+	if fixed == 0 {
+		log.Warning("No autofixable issues found")
+		return nil
+	}
+
+	fixedContent, err := devObj.Data.Marshal()
+	if err != nil {
+		return errors.Wrap(err, "unable to serialize the fixed devfile")
+	}
+	if err := ioutil.WriteFile(o.devfilePath, fixedContent, 0644); err != nil {
+		return errors.Wrapf(err, "unable to write %s", o.devfilePath)
+	}
+
+	log.Successf("Fixed %d issue(s) in %s", fixed, o.devfilePath)
+	return nil
+}
+
+// NewCmdLint implements the `odo devfile lint` command.
+func NewCmdLint(name, fullName string) *cobra.Command {
+	o := NewLintOptions()
+	lintCmd := &cobra.Command{
+		Use:     name,
+		Short:   "Lint a devfile and report fixable issues",
+		Long:    "Runs the devfile pre-flight linter and reports diagnostics with source-line context, optionally fixing the safe ones in place.",
+		Example: fmt.Sprintf("  %s\n  %s --fix", fullName, fullName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.devfilePath == "" {
+				o.devfilePath = defaultDevfilePath
+			}
+			return o.Run()
+		},
+	}
+	lintCmd.Flags().StringVar(&o.devfilePath, "devfile", "", "path to the devfile to lint")
+	lintCmd.Flags().BoolVar(&o.fix, "fix", false, "rewrite the devfile in place, applying every safe autofix")
+	return lintCmd
+}
@@ -0,0 +1,86 @@
+package pushtarget
+
+import (
+	"os"
+
+	"github.com/openshift/odo/pkg/log"
+	"github.com/openshift/odo/pkg/preference"
+	"github.com/spf13/cobra"
+)
+
+// EnvPushTarget lets `--push-target` be set for a single invocation without
+// touching preference.yaml, mirroring how the docker push target is
+// selected today.
+const EnvPushTarget = "ODO_PUSH_TARGET"
+
+// Kind identifies which backend odo pushes devfile components to.
+type Kind string
+
+const (
+	// KindKubernetes pushes components to a Kubernetes/OpenShift cluster.
+	KindKubernetes Kind = "kubernetes"
+	// KindDocker pushes components to a local Docker daemon.
+	KindDocker Kind = "docker"
+	// KindPodman pushes components to a local, usually rootless, Podman.
+	KindPodman Kind = "podman"
+)
+
+// IsPushTargetPodman returns true if the push target has been set to
+// podman, either for this invocation via --push-target/ODO_PUSH_TARGET or
+// persistently via `odo preference set pushtarget podman`.
+func IsPushTargetPodman() bool {
+	if kind := os.Getenv(EnvPushTarget); kind != "" {
+		return kind == string(KindPodman)
+	}
+
+	prefClient, err := preference.NewClient()
+	if err != nil {
+		log.Warningf("Unable to load preference settings (%v); defaulting push target to kubernetes", err)
+		return false
+	}
+	return prefClient.PushTarget() == string(KindPodman)
+}
+
+// Get returns the push target selected via `--push-target` or the
+// `pushtarget` preference key, defaulting to KindKubernetes. It supersedes
+// picking a backend by chaining IsPushTargetDocker()/IsPushTargetPodman()
+// checks at every call site.
+func Get() Kind {
+	switch {
+	case IsPushTargetDocker():
+		return KindDocker
+	case IsPushTargetPodman():
+		return KindPodman
+	default:
+		return KindKubernetes
+	}
+}
+
+// AddFlag registers the --push-target flag on cmd. Unlike
+// `odo preference set pushtarget`, it only overrides the target for this
+// one invocation, by setting ODO_PUSH_TARGET for the process before Get()
+// (or IsPushTargetDocker/IsPushTargetPodman) is ever consulted.
+func AddFlag(cmd *cobra.Command) {
+	var kind string
+	cmd.PersistentFlags().StringVar(&kind, "push-target", "", "override the push target for this invocation only (kubernetes, docker, or podman)")
+	cmd.PersistentPreRunE = chainPreRunE(cmd.PersistentPreRunE, func(*cobra.Command, []string) error {
+		if kind != "" {
+			return os.Setenv(EnvPushTarget, kind)
+		}
+		return nil
+	})
+}
+
+// chainPreRunE returns a PersistentPreRunE that runs existing (if any) then
+// next, so AddFlag can be called alongside other flags without clobbering
+// a PersistentPreRunE a command already set.
+func chainPreRunE(existing, next func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if existing != nil {
+			if err := existing(cmd, args); err != nil {
+				return err
+			}
+		}
+		return next(cmd, args)
+	}
+}
@@ -0,0 +1,72 @@
+// Package preference loads and persists the devfile-registry-token slice
+// of preference.yaml: the per-URL token map `odo preference set` writes to
+// so a private registry or git provider URL can be authenticated without
+// --token or ODO_DEVFILE_TOKEN on every invocation.
+package preference
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Path is the on-disk location of preference.yaml.
+var Path = filepath.Join(os.Getenv("HOME"), ".odo", "preference.yaml")
+
+// preference is the on-disk shape of the fields this package knows about.
+// preference.yaml carries other odo settings too; they're simply ignored
+// (and preserved verbatim isn't attempted here, since nothing in this
+// package ever writes the file back).
+type preference struct {
+	// DevfileRegistryTokens maps a devfile/parent URL to the token
+	// `odo preference set devfileregistrytoken <url> <token>` saved for
+	// it, checked by auth.Resolve before the OS keyring.
+	DevfileRegistryTokens map[string]string `yaml:"devfileRegistryTokens,omitempty"`
+	// PushTarget is the backend `odo push` targets by default, set via
+	// `odo preference set pushtarget <kubernetes|docker|podman>`, checked
+	// by pushtarget.Get() once --push-target/ODO_PUSH_TARGET don't apply.
+	PushTarget string `yaml:"pushTarget,omitempty"`
+}
+
+// Client gives read access to preference.yaml.
+type Client struct {
+	pref preference
+}
+
+// NewClient loads preference.yaml. A missing file is not an error; it just
+// means no preference has been set yet, so every accessor returns its zero
+// value.
+func NewClient() (*Client, error) {
+	content, err := ioutil.ReadFile(Path)
+	if os.IsNotExist(err) {
+		return &Client{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", Path)
+	}
+
+	var pref preference
+	if err := yaml.Unmarshal(content, &pref); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %s", Path)
+	}
+	return &Client{pref: pref}, nil
+}
+
+// DevfileRegistryTokens returns the per-URL devfile token map saved in
+// preference.yaml, checked by auth.Resolve before the OS keyring. It is
+// never nil.
+func (c *Client) DevfileRegistryTokens() map[string]string {
+	if c.pref.DevfileRegistryTokens == nil {
+		return map[string]string{}
+	}
+	return c.pref.DevfileRegistryTokens
+}
+
+// PushTarget returns the `pushtarget` preference key, or "" if it hasn't
+// been set.
+func (c *Client) PushTarget() string {
+	return c.pref.PushTarget
+}
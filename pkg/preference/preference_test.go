@@ -0,0 +1,45 @@
+package preference
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClient_MissingFile(t *testing.T) {
+	dir := t.TempDir()
+	old := Path
+	Path = filepath.Join(dir, "does-not-exist.yaml")
+	defer func() { Path = old }()
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v, want nil for a missing preference.yaml", err)
+	}
+	if tokens := client.DevfileRegistryTokens(); len(tokens) != 0 {
+		t.Errorf("DevfileRegistryTokens() = %v, want empty", tokens)
+	}
+}
+
+func TestDevfileRegistryTokens(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "preference.yaml")
+	content := "devfileRegistryTokens:\n  https://git.example.com/foo/bar.git: s3cr3t\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("unable to write test preference.yaml: %v", err)
+	}
+
+	old := Path
+	Path = path
+	defer func() { Path = old }()
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	tokens := client.DevfileRegistryTokens()
+	if got := tokens["https://git.example.com/foo/bar.git"]; got != "s3cr3t" {
+		t.Errorf("DevfileRegistryTokens()[url] = %q, want %q", got, "s3cr3t")
+	}
+}
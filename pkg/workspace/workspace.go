@@ -0,0 +1,120 @@
+// Package workspace parses odo-workspace.yaml, the manifest `odo push
+// --all` uses to discover and order the push of multiple devfile-based
+// components that live in sub-directories of a single checkout.
+package workspace
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// FileName is the manifest odo looks for in the directory `odo push --all`
+// is run from.
+const FileName = "odo-workspace.yaml"
+
+// ComponentSpec is one entry of the top-level `components:` list in
+// odo-workspace.yaml.
+type ComponentSpec struct {
+	// Name identifies the component in log output and in other
+	// components' DependsOn lists.
+	Name string `yaml:"name"`
+	// Path is the component's devfile directory, relative to the
+	// workspace file.
+	Path string `yaml:"path"`
+	// DependsOn lists the Names of components that must be pushed
+	// successfully before this one is pushed.
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+}
+
+// Workspace is the parsed contents of odo-workspace.yaml.
+type Workspace struct {
+	Components []ComponentSpec `yaml:"components"`
+}
+
+// Load reads and parses the odo-workspace.yaml found in dir.
+func Load(dir string) (*Workspace, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", FileName)
+	}
+
+	var w Workspace
+	if err := yaml.Unmarshal(content, &w); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %s", FileName)
+	}
+
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (w *Workspace) validate() error {
+	seen := make(map[string]bool, len(w.Components))
+	for _, c := range w.Components {
+		if c.Name == "" {
+			return errors.Errorf("%s: component with empty name", FileName)
+		}
+		if seen[c.Name] {
+			return errors.Errorf("%s: duplicate component name %q", FileName, c.Name)
+		}
+		seen[c.Name] = true
+	}
+	for _, c := range w.Components {
+		for _, dep := range c.DependsOn {
+			if !seen[dep] {
+				return errors.Errorf("%s: component %q depends on unknown component %q", FileName, c.Name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// Plan groups w.Components into ordered batches: every component in batch
+// N only depends on components in batches before it, so all components
+// within a single batch can be pushed concurrently. It returns an error if
+// DependsOn edges form a cycle.
+func (w *Workspace) Plan() ([][]ComponentSpec, error) {
+	byName := make(map[string]ComponentSpec, len(w.Components))
+	remaining := make(map[string][]string, len(w.Components))
+	for _, c := range w.Components {
+		byName[c.Name] = c
+		remaining[c.Name] = append([]string{}, c.DependsOn...)
+	}
+
+	var batches [][]ComponentSpec
+	done := make(map[string]bool, len(w.Components))
+
+	for len(done) < len(w.Components) {
+		var batch []ComponentSpec
+		for name, deps := range remaining {
+			if done[name] {
+				continue
+			}
+			if allDone(deps, done) {
+				batch = append(batch, byName[name])
+			}
+		}
+		if len(batch) == 0 {
+			return nil, errors.Errorf("%s: circular dependsOn relationship detected among the remaining components", FileName)
+		}
+		for _, c := range batch {
+			done[c.Name] = true
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+func allDone(deps []string, done map[string]bool) bool {
+	for _, dep := range deps {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package workspace
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func writeWorkspaceFile(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, FileName), []byte(content), 0644); err != nil {
+		t.Fatalf("unable to write %s: %v", FileName, err)
+	}
+}
+
+func batchNames(batch []ComponentSpec) []string {
+	names := make([]string, len(batch))
+	for i, c := range batch {
+		names[i] = c.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestLoad_ValidWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkspaceFile(t, dir, `
+components:
+  - name: backend
+    path: backend
+  - name: frontend
+    path: frontend
+    dependsOn: [backend]
+`)
+
+	w, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(w.Components) != 2 {
+		t.Fatalf("Load() got %d components, want 2", len(w.Components))
+	}
+}
+
+func TestLoad_DuplicateName(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkspaceFile(t, dir, `
+components:
+  - name: backend
+    path: a
+  - name: backend
+    path: b
+`)
+
+	if _, err := Load(dir); err == nil {
+		t.Error("Load() with duplicate component names should return an error")
+	}
+}
+
+func TestLoad_UnknownDependency(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkspaceFile(t, dir, `
+components:
+  - name: frontend
+    path: frontend
+    dependsOn: [does-not-exist]
+`)
+
+	if _, err := Load(dir); err == nil {
+		t.Error("Load() with an unknown dependsOn reference should return an error")
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("Load() with no odo-workspace.yaml should return an error")
+	}
+}
+
+func TestPlan_OrdersByDependency(t *testing.T) {
+	w := &Workspace{Components: []ComponentSpec{
+		{Name: "frontend", DependsOn: []string{"backend"}},
+		{Name: "backend"},
+		{Name: "worker", DependsOn: []string{"backend"}},
+	}}
+
+	batches, err := w.Plan()
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("Plan() got %d batches, want 2", len(batches))
+	}
+	if got, want := batchNames(batches[0]), []string{"backend"}; !equalStrings(got, want) {
+		t.Errorf("batch 0 = %v, want %v", got, want)
+	}
+	if got, want := batchNames(batches[1]), []string{"frontend", "worker"}; !equalStrings(got, want) {
+		t.Errorf("batch 1 = %v, want %v", got, want)
+	}
+}
+
+func TestPlan_DetectsCycle(t *testing.T) {
+	w := &Workspace{Components: []ComponentSpec{
+		{Name: "a", DependsOn: []string{"b"}},
+		{Name: "b", DependsOn: []string{"a"}},
+	}}
+
+	if _, err := w.Plan(); err == nil {
+		t.Error("Plan() with a circular dependsOn relationship should return an error")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}